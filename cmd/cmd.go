@@ -3,6 +3,7 @@ package cmd
 import (
 	"bufio"
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"os/exec"
 	"regexp"
@@ -33,6 +34,223 @@ const FishDockerCommandChainExactlyMatches = `function __fish_docker_command_cha
     string match -q -r '^--?\w+' -- $cmd[(math 1 + (count $argv))]
 end`
 
+// FishDockerContainerRmCandidates offers exited/created containers for
+// `rm`, or every container once -f/--force is already on the command line.
+const FishDockerContainerRmCandidates = `function __fish_docker_container_rm_candidates
+    if commandline -poc | string match -qr -- '^-\w*f\w*$|^--force$'
+        docker container ls --all --format '{{.Names}}'
+    else
+        docker container ls --all --filter status=exited --filter status=created --format '{{.Names}}'
+    end
+end`
+
+// FishDockerImageRmiCandidates offers only dangling images for `rmi`, or
+// every image once -f/--force is already on the command line.
+const FishDockerImageRmiCandidates = `function __fish_docker_image_rmi_candidates
+    if commandline -poc | string match -qr -- '^-\w*f\w*$|^--force$'
+        docker image ls --format '{{.Repository}}:{{.Tag}}'
+    else
+        docker image ls --filter dangling=true --format '{{.Repository}}:{{.Tag}}'
+    end
+end`
+
+// FishDockerNetworkConnectCandidates offers containers not yet attached to
+// the network being connected to. It bails when invoked for the NETWORK
+// positional itself (not yet typed), since at that point the last token on
+// the command line is still "connect", not a network name.
+const FishDockerNetworkConnectCandidates = `function __fish_docker_network_connect_candidates
+    set -l network (commandline -poc)[-1]
+    if test $network = connect
+        return
+    end
+    set -l attached (docker network inspect $network --format '{{range .Containers}}{{.Name}}\n{{end}}' 2>/dev/null)
+    for name in (docker container ls --format '{{.Names}}')
+        if not contains -- $name $attached
+            echo $name
+        end
+    end
+end`
+
+// FishDockerNetworkDisconnectCandidates offers containers already attached
+// to the network being disconnected from, with the same NETWORK-positional
+// guard as FishDockerNetworkConnectCandidates.
+const FishDockerNetworkDisconnectCandidates = `function __fish_docker_network_disconnect_candidates
+    set -l network (commandline -poc)[-1]
+    if test $network = disconnect
+        return
+    end
+    docker network inspect $network --format '{{range .Containers}}{{.Name}}\n{{end}}' 2>/dev/null
+end`
+
+// FishDockerComposeFile locates the compose file a `docker compose` chain
+// would actually use: whatever -f/--file is already on the command line, or
+// else the nearest compose.yaml/docker-compose.yml walking up from $PWD.
+const FishDockerComposeFile = `function __fish_docker_compose_file
+    set -l tokens (commandline -poc)
+    for i in (seq (count $tokens))
+        if contains -- $tokens[$i] -f --file
+            echo $tokens[(math $i + 1)]
+            return 0
+        end
+    end
+    set -l dir $PWD
+    while true
+        for name in compose.yaml compose.yml docker-compose.yaml docker-compose.yml
+            if test -f $dir/$name
+                echo $dir/$name
+                return 0
+            end
+        end
+        if test $dir = /
+            return 1
+        end
+        set dir (dirname $dir)
+    end
+end`
+
+// FishDockerComposeFileCandidates offers *.yml/*.yaml files in the current
+// directory, for completing -f/--file's value.
+const FishDockerComposeFileCandidates = `function __fish_docker_compose_file_candidates
+    for file in *.yml *.yaml
+        test -e $file; and echo $file
+    end
+end`
+
+// FishDockerComposeServices lists the service names declared under the
+// top-level `services:` key of the given (or located) compose file. The
+// awk scraper detects the indent width from the first service entry
+// instead of assuming two spaces, since compose YAML has no fixed style.
+const FishDockerComposeServices = `function __fish_docker_compose_services
+    set -l file $argv[1]
+    if test -z "$file"
+        set file (__fish_docker_compose_file)
+        or return
+    end
+    awk '
+        /^services:[[:space:]]*$/ { in_services = 1; indent = -1; next }
+        in_services && /^[^[:space:]]/ { in_services = 0 }
+        in_services && indent == -1 && /^[ \t]+[A-Za-z0-9_.-]+:/ {
+            match($0, /^[ \t]+/); indent = RLENGTH
+        }
+        in_services && indent != -1 {
+            match($0, /^[ \t]*/)
+            if (RLENGTH == indent) {
+                line = $0
+                sub(/^[ \t]+/, "", line); sub(/:.*/, "", line)
+                print line
+            }
+        }
+    ' $file
+end`
+
+// FishDockerStackBundleFile locates the bundle a `docker stack` chain would
+// actually use: whatever --bundle-file is already on the command line, or
+// else the first *.dab in the current directory.
+const FishDockerStackBundleFile = `function __fish_docker_stack_bundle_file
+    set -l tokens (commandline -poc)
+    for i in (seq (count $tokens))
+        if contains -- $tokens[$i] --bundle-file
+            echo $tokens[(math $i + 1)]
+            return 0
+        end
+    end
+    for file in *.dab
+        echo $file
+        return 0
+    end
+    return 1
+end`
+
+// FishDockerStackBundleFileCandidates offers *.dab files in the current
+// directory, for completing --bundle-file's value.
+const FishDockerStackBundleFileCandidates = `function __fish_docker_stack_bundle_file_candidates
+    for file in *.dab
+        test -e $file; and echo $file
+    end
+end`
+
+// FishDockerStackBundleServices lists the service names declared under the
+// "Services" key of the given (or located) DAB bundle file.
+const FishDockerStackBundleServices = `function __fish_docker_stack_bundle_services
+    set -l file $argv[1]
+    if test -z "$file"
+        set file (__fish_docker_stack_bundle_file)
+        or return
+    end
+    jq -r '.Services | keys[]' $file 2>/dev/null
+end`
+
+// FishDockerStackServices lists services from whichever of --bundle-file
+// (a DAB JSON bundle) or --compose-file (a compose YAML file) is already on
+// the command line, falling back to the running swarm's services if
+// neither flag has been given yet.
+const FishDockerStackServices = `function __fish_docker_stack_services
+    set -l tokens (commandline -poc)
+    for i in (seq (count $tokens))
+        if contains -- $tokens[$i] --bundle-file
+            __fish_docker_stack_bundle_services $tokens[(math $i + 1)]
+            return
+        end
+        if contains -- $tokens[$i] --compose-file
+            __fish_docker_compose_services $tokens[(math $i + 1)]
+            return
+        end
+    end
+    docker service ls --format '{{.Name}}'
+end`
+
+// FishDockerDockerfileFile locates the Dockerfile a build invocation would
+// actually use: whatever -f/--file is already on the command line, or else
+// ./Dockerfile.
+const FishDockerDockerfileFile = `function __fish_docker_dockerfile_file
+    set -l tokens (commandline -poc)
+    for i in (seq (count $tokens))
+        if contains -- $tokens[$i] -f --file
+            echo $tokens[(math $i + 1)]
+            return 0
+        end
+    end
+    echo Dockerfile
+end`
+
+// FishDockerDockerfileStages scans the Dockerfile for `FROM ... AS <stage>`
+// lines and prints the stage names. A stage built from an ARG (e.g. `FROM
+// $BASE AS ${STAGE}`) is passed through as written rather than dropped,
+// since resolving it would mean evaluating build args.
+const FishDockerDockerfileStages = `function __fish_docker_dockerfile_stages
+    set -l file (__fish_docker_dockerfile_file)
+    test -f $file; or return
+    string match -r -i --groups-only -- '^\s*FROM\s+\S+\s+AS\s+(\S+)\s*$' <$file
+end`
+
+// FishDockerCached memoizes an expensive `docker ... ls` invocation under
+// $XDG_CACHE_HOME/fish-docker-completion for $FISH_DOCKER_COMPLETION_TTL
+// seconds (default 2), keyed by $DOCKER_HOST/$DOCKER_CONTEXT so switching
+// context invalidates it, with writes going through a temp file + mv so a
+// reader never sees a partial file.
+const FishDockerCached = `function __fish_docker_cached
+    set -l key $argv[1]
+    set -l cmd $argv[2]
+    set -l ttl $FISH_DOCKER_COMPLETION_TTL
+    test -n "$ttl"; or set ttl 2
+    set -l cache_root $XDG_CACHE_HOME
+    test -n "$cache_root"; or set cache_root $HOME/.cache
+    set -l cache_dir $cache_root/fish-docker-completion
+    mkdir -p $cache_dir
+    set -l cache_file $cache_dir/(string escape --style=var -- $key"-$DOCKER_HOST-$DOCKER_CONTEXT")
+    if test -f $cache_file
+        set -l age (math (date +%s) - (stat -c %Y $cache_file 2>/dev/null; or stat -f %m $cache_file))
+        if test $age -lt $ttl
+            cat $cache_file
+            return
+        end
+    end
+    set -l tmp (mktemp $cache_file.XXXXXX)
+    eval $cmd >$tmp 2>/dev/null
+    mv $tmp $cache_file
+    cat $cache_file
+end`
+
 type Command struct {
 	Chain       []string
 	Desc        string
@@ -43,9 +261,11 @@ type Command struct {
 }
 
 type Option struct {
-	Desc  string
-	Long  string
-	Short string
+	Desc          string
+	Long          string
+	Short         string
+	Values        []string
+	ValuesCommand string
 }
 
 type Argument struct {
@@ -68,54 +288,289 @@ const (
 )
 
 var Arguments = map[int]Argument{
-	ArgumentNumberDockerConfig:    {"Config", "(docker config ls)"},
-	ArgumentNumberDockerContainer: {"Container", "(docker container ls --all --format='{{.Names}}')"},
-	ArgumentNumberDockerImage:     {"Image", "(docker image ls --format='{{.Repository}}:{{.Tag}}')"},
-	ArgumentNumberDockerNetwork:   {"Network", "(docker network ls --format='{{.Name}}')"},
-	ArgumentNumberDockerNode:      {"Node", "(docker node ls --format='{{.Name}}')"},
-	ArgumentNumberDockerPlugin:    {"Plugin", "(docker plugin ls --format='{{.Name}}')"},
-	ArgumentNumberDockerSecret:    {"Secret", "(docker secret ls --format='{{.Name}}')"},
-	ArgumentNumberDockerService:   {"Service", "(docker service ls --format='{{.Name}}')"},
-	ArgumentNumberDockerStack:     {"Stack", "(docker stack ls --format='{{.Name}}')"},
-	ArgumentNumberDockerVolume:    {"Volume", "(docker volume ls --format='{{.Name}}')"},
+	ArgumentNumberDockerConfig:    {"Config", "(__fish_docker_cached configs \"docker config ls\")"},
+	ArgumentNumberDockerContainer: {"Container", "(__fish_docker_cached containers \"docker container ls --all --format='{{.Names}}'\")"},
+	ArgumentNumberDockerImage:     {"Image", "(__fish_docker_cached images \"docker image ls --format='{{.Repository}}:{{.Tag}}'\")"},
+	ArgumentNumberDockerNetwork:   {"Network", "(__fish_docker_cached networks \"docker network ls --format='{{.Name}}'\")"},
+	ArgumentNumberDockerNode:      {"Node", "(__fish_docker_cached nodes \"docker node ls --format='{{.Name}}'\")"},
+	ArgumentNumberDockerPlugin:    {"Plugin", "(__fish_docker_cached plugins \"docker plugin ls --format='{{.Name}}'\")"},
+	ArgumentNumberDockerSecret:    {"Secret", "(__fish_docker_cached secrets \"docker secret ls --format='{{.Name}}'\")"},
+	ArgumentNumberDockerService:   {"Service", "(__fish_docker_cached services \"docker service ls --format='{{.Name}}'\")"},
+	ArgumentNumberDockerStack:     {"Stack", "(__fish_docker_cached stacks \"docker stack ls --format='{{.Name}}'\")"},
+	ArgumentNumberDockerVolume:    {"Volume", "(__fish_docker_cached volumes \"docker volume ls --format='{{.Name}}'\")"},
 	ArgumentNumberFile:            {"", "(ls)"},
 }
 
+// chainArgumentKey identifies a single positional argument (by its bit in
+// ArgumentNumber*) on a specific command chain, e.g. {"container rm",
+// ArgumentNumberDockerContainer}.
+type chainArgumentKey struct {
+	chain    string
+	argument int
+}
+
+// ChainArgumentOverrides narrows the generic Arguments listing for specific
+// subcommands whose semantics restrict which resources make sense, e.g.
+// `docker start` only accepts stopped containers. Keys are matched against
+// both the grouped form ("container start") and the flat legacy form
+// ("start") since docker's help tree exposes both. When no entry matches,
+// Completion falls back to the generic listing in Arguments.
+var ChainArgumentOverrides = map[chainArgumentKey]string{
+	{"start", ArgumentNumberDockerContainer}:              "(docker container ls --all --filter status=created --filter status=exited --format '{{.Names}}')",
+	{"container start", ArgumentNumberDockerContainer}:    "(docker container ls --all --filter status=created --filter status=exited --format '{{.Names}}')",
+	{"unpause", ArgumentNumberDockerContainer}:            "(docker container ls --filter status=paused --format '{{.Names}}')",
+	{"container unpause", ArgumentNumberDockerContainer}:  "(docker container ls --filter status=paused --format '{{.Names}}')",
+	{"attach", ArgumentNumberDockerContainer}:             "(docker container ls --filter status=running --format '{{.Names}}')",
+	{"container attach", ArgumentNumberDockerContainer}:   "(docker container ls --filter status=running --format '{{.Names}}')",
+	{"rm", ArgumentNumberDockerContainer}:                 "(__fish_docker_container_rm_candidates)",
+	{"container rm", ArgumentNumberDockerContainer}:       "(__fish_docker_container_rm_candidates)",
+	{"rmi", ArgumentNumberDockerImage}:                    "(__fish_docker_image_rmi_candidates)",
+	{"image rm", ArgumentNumberDockerImage}:               "(__fish_docker_image_rmi_candidates)",
+	{"image rmi", ArgumentNumberDockerImage}:              "(__fish_docker_image_rmi_candidates)",
+	{"network connect", ArgumentNumberDockerContainer}:    "(__fish_docker_network_connect_candidates)",
+	{"network disconnect", ArgumentNumberDockerContainer}: "(__fish_docker_network_disconnect_candidates)",
+}
+
+// chainOptionKey identifies a single flag (by its long name) on a specific
+// command chain, e.g. {"compose", "file"}.
+type chainOptionKey struct {
+	chain string
+	long  string
+}
+
+// ChainOptionValueOverrides narrows a flag's value completion for chains
+// where the generic Arguments/FlagValueOverrides listing doesn't apply
+// because the same flag name means something different depending on the
+// command group, e.g. `docker compose -f` takes a compose file while
+// `docker build -f` takes a Dockerfile.
+var ChainOptionValueOverrides = map[chainOptionKey]string{
+	{"compose", "file"}:              "(__fish_docker_compose_file_candidates)",
+	{"stack deploy", "bundle-file"}:  "(__fish_docker_stack_bundle_file_candidates)",
+	{"stack deploy", "compose-file"}: "(__fish_docker_compose_file_candidates)",
+	{"build", "target"}:              "(__fish_docker_dockerfile_stages)",
+	{"buildx build", "target"}:       "(__fish_docker_dockerfile_stages)",
+	{"image build", "target"}:        "(__fish_docker_dockerfile_stages)",
+	// `COPY --from=<TAB>` inside a Dockerfile isn't reachable from here: this
+	// table only drives completion of `docker ...` invocations, not the
+	// contents of a file being edited in $EDITOR.
+}
+
+// serviceArgumentOverride picks the completion source for a SERVICE
+// positional under the "compose" and "stack" command groups, where the
+// generic `docker service ls` listing doesn't apply: compose services live
+// in a compose file rather than the swarm, and stack services may come from
+// either a not-yet-deployed bundle/compose file or already-running swarm
+// services.
+func (c *Command) serviceArgumentOverride() (string, bool) {
+	if len(c.Chain) < 2 {
+		return "", false
+	}
+	switch c.Chain[1] {
+	case "compose":
+		return "(__fish_docker_compose_services)", true
+	case "stack":
+		return "(__fish_docker_stack_services)", true
+	}
+	return "", false
+}
+
+// Source builds a Command for a single chain/subcommand pair from whatever
+// raw material it knows how to fetch (prose --help text, a JSON-formatted
+// help call, a generated shell-completion script, ...). Forge tries each
+// registered Source in turn and keeps the first one that succeeds, so a
+// CLI that exposes richer metadata than Docker's --help prose can plug in
+// without Forge itself needing to know about it.
+type Source interface {
+	Forge(cmd string, chain []string, desc string) (*Command, error)
+}
+
+// Sources is the list Forge consults, in order. Structured sources come
+// first since they're cheaper to parse correctly; HelpTextSource is last
+// because today it's the only one that actually works against Docker's
+// CLI, and it should always be able to produce a Command.
+var Sources = []Source{
+	JSONHelpSource{},
+	CobraCompletionSource{},
+	HelpTextSource{},
+}
+
 func Forge(cmd string, chain []string, desc string) (c *Command, err error) {
+	for _, src := range Sources {
+		if c, err = src.Forge(cmd, chain, desc); err == nil {
+			return c, nil
+		}
+	}
+	return nil, err
+}
+
+// HelpTextSource is the original source: it scrapes `<chain> <cmd> --help`
+// prose, relying on the "Usage:", "Options:", and "*Commands:" headings
+// staying put and the description column lining up well enough for
+// splitDescription's majority-vote heuristic to find it.
+type HelpTextSource struct{}
+
+func (HelpTextSource) Forge(cmd string, chain []string, desc string) (*Command, error) {
 	arg := make([]string, 0)
 	arg = append(arg, chain...)
 	arg = append(arg, cmd)
 	arg = append(arg, "--help")
 	msg, err := exec.Command(arg[0], arg[1:]...).Output()
 	if err != nil {
-		return
+		return nil, err
 	}
 	arg = arg[:len(arg)-1] // Remove "--help"
-	c = &Command{}
+	c := &Command{}
 	c.Chain = arg
 	c.Desc = desc
 	c.helpMessage = msg
 	c.setArgument()
-	if err = c.setOptions(); err != nil {
-		return
+	if err := c.setOptions(); err != nil {
+		return nil, err
 	}
-	if err = c.setSubcommands(); err != nil {
-		return
+	if err := c.setSubcommands(); err != nil {
+		return nil, err
 	}
-	return
+	return c, nil
+}
+
+// jsonHelpDoc is the shape Forge expects from `<chain> <cmd> --help
+// --format '{{json .}}'`, modeled after cobra's own Command fields. Newer
+// Docker CLI builds may grow support for this; until then (and for CLIs
+// that never will), JSONHelpSource simply errors and Forge falls back to
+// HelpTextSource. Args carries the resource type cobra's
+// ValidArgsFunction already knows for each positional (e.g. "container",
+// "image") so Arguments can be built directly from that, the same way
+// Docker's own shell-completion scripts read it off ValidArgsFunction
+// instead of re-deriving it from the usage line.
+type jsonHelpDoc struct {
+	Use   string `json:"use"`
+	Short string `json:"short"`
+	Args  []struct {
+		Type string `json:"type"`
+	} `json:"args"`
+	Flags []struct {
+		Name      string `json:"name"`
+		Shorthand string `json:"shorthand"`
+		Usage     string `json:"usage"`
+	} `json:"flags"`
+	Commands []struct {
+		Name  string `json:"name"`
+		Short string `json:"short"`
+	} `json:"commands"`
+}
+
+// jsonArgTypeBits maps a jsonHelpDoc positional's resource Type to its
+// ArgumentNumber* bit. Unlike parseArgumentNumber, this is a direct lookup
+// against metadata the CLI reports for itself -- no regex over a usage
+// line is involved.
+var jsonArgTypeBits = map[string]int{
+	"config":    ArgumentNumberDockerConfig,
+	"container": ArgumentNumberDockerContainer,
+	"image":     ArgumentNumberDockerImage,
+	"network":   ArgumentNumberDockerNetwork,
+	"node":      ArgumentNumberDockerNode,
+	"plugin":    ArgumentNumberDockerPlugin,
+	"secret":    ArgumentNumberDockerSecret,
+	"service":   ArgumentNumberDockerService,
+	"stack":     ArgumentNumberDockerStack,
+	"volume":    ArgumentNumberDockerVolume,
+	"file":      ArgumentNumberFile,
+}
+
+// JSONHelpSource reads structured command metadata instead of scraping
+// prose, when the CLI under test supports `--help --format '{{json .}}'`.
+// No released Docker CLI actually honors that flag yet, so today this
+// source always errors and every chain falls back to HelpTextSource; it's
+// forward-looking scaffolding for if/when `--help` grows a structured
+// output mode, not a working replacement for the prose scraper.
+type JSONHelpSource struct{}
+
+func (JSONHelpSource) Forge(cmd string, chain []string, desc string) (*Command, error) {
+	arg := make([]string, 0)
+	arg = append(arg, chain...)
+	arg = append(arg, cmd, "--help", "--format", "{{json .}}")
+	out, err := exec.Command(arg[0], arg[1:]...).Output()
+	if err != nil {
+		return nil, err
+	}
+	var doc jsonHelpDoc
+	if err := json.Unmarshal(out, &doc); err != nil {
+		return nil, err
+	}
+	c := &Command{
+		Chain: append(append([]string{}, chain...), cmd),
+		Desc:  desc,
+	}
+	for _, a := range doc.Args {
+		c.Arguments |= jsonArgTypeBits[a.Type]
+	}
+	for _, f := range doc.Flags {
+		values, valuesCommand := parseValuesFromDesc(f.Usage), ""
+		if override, ok := FlagValueOverrides[f.Name]; ok {
+			values, valuesCommand = override.Values, override.ValuesCommand
+		}
+		if override, ok := ChainOptionValueOverrides[chainOptionKey{c.chainSuffix(), f.Name}]; ok {
+			values, valuesCommand = nil, override
+		}
+		c.Options = append(c.Options, &Option{
+			Desc:          f.Usage,
+			Long:          f.Name,
+			Short:         f.Shorthand,
+			Values:        values,
+			ValuesCommand: valuesCommand,
+		})
+	}
+	for _, sc := range doc.Commands {
+		subcommand, err := Forge(sc.Name, c.Chain, sc.Short)
+		if err != nil {
+			return nil, err
+		}
+		c.Subcommands = append(c.Subcommands, subcommand)
+	}
+	return c, nil
+}
+
+// CobraCompletionSource would parse the function bodies cobra's own
+// `completion bash`/`zsh` generators emit (flag names, descriptions, and
+// ValidArgsFunction hints) instead of --help prose. That script describes
+// the whole CLI tree in one go rather than one chain at a time, so there's
+// no clean per-chain Forge call to make against it; until that's worked
+// out this source always declines, leaving HelpTextSource to handle it.
+type CobraCompletionSource struct{}
+
+func (CobraCompletionSource) Forge(cmd string, chain []string, desc string) (*Command, error) {
+	return nil, fmt.Errorf("cobra completion source not yet implemented")
 }
 
 func (c *Command) ChainString() string {
 	return strings.Join(c.Chain, " ")
 }
 
+// chainSuffix returns the chain with the leading "docker" dropped, e.g.
+// "container rm" for []string{"docker", "container", "rm"}. It's the key
+// space ChainArgumentOverrides matches against.
+func (c *Command) chainSuffix() string {
+	if len(c.Chain) == 0 {
+		return ""
+	}
+	return strings.Join(c.Chain[1:], " ")
+}
+
 func (c *Command) Completion() string {
 	var s string
 	for k, v := range Arguments {
 		if k&c.Arguments == 0 {
 			continue
 		}
-		s += fmt.Sprintf("complete -c docker -n '__fish_docker_command_chain_exactly_matches %s' -a %q -d %q\n", c.ChainString(), v.Command, v.Type)
+		listing := v.Command
+		if k == ArgumentNumberDockerService {
+			if override, ok := c.serviceArgumentOverride(); ok {
+				listing = override
+			}
+		} else if override, ok := ChainArgumentOverrides[chainArgumentKey{c.chainSuffix(), k}]; ok {
+			listing = override
+		}
+		s += fmt.Sprintf("complete -c docker -n '__fish_docker_command_chain_exactly_matches %s' -a %q -d %q\n", c.ChainString(), listing, v.Type)
 	}
 	for _, sc := range c.Subcommands {
 		s += fmt.Sprintf("complete -c docker -n '__fish_docker_command_chain_exactly_matches %s' -a %s -d %q\n", c.ChainString(), sc.Chain[len(sc.Chain)-1], sc.Desc)
@@ -128,26 +583,67 @@ func (c *Command) Completion() string {
 		if opt.Long != "" {
 			s += fmt.Sprintf(" -l %s", opt.Long)
 		}
+		if opt.ValuesCommand != "" {
+			s += fmt.Sprintf(" -xa %q", opt.ValuesCommand)
+		} else if len(opt.Values) > 0 {
+			s += fmt.Sprintf(" -xa %q", strings.Join(opt.Values, " "))
+		}
 		s += fmt.Sprintf(" -d %q\n", opt.Desc)
 	}
 	return s
 }
 
+// enumDescPattern matches inline enumerations in flag descriptions such as
+// "(json-file|syslog|journald|none)".
+var enumDescPattern = regexp.MustCompile(`\(([a-zA-Z0-9_-]+(?:\|[a-zA-Z0-9_-]+)+)\)`)
+
+// oneOfDescPattern matches prose enumerations such as "one of no,
+// on-failure, or always".
+var oneOfDescPattern = regexp.MustCompile(`(?i)one of ((?:[a-zA-Z0-9_-]+[,\s]*)+[a-zA-Z0-9_-]+)`)
+
+// parseValuesFromDesc extracts a flag's valid values from its help text, if
+// the text spells them out as either "(a|b|c)" or "one of a, b, or c".
+func parseValuesFromDesc(desc string) []string {
+	if m := enumDescPattern.FindStringSubmatch(desc); m != nil {
+		return strings.Split(m[1], "|")
+	}
+	if m := oneOfDescPattern.FindStringSubmatch(desc); m != nil {
+		var values []string
+		for _, word := range regexp.MustCompile(`[a-zA-Z0-9_-]+`).FindAllString(m[1], -1) {
+			if strings.EqualFold(word, "or") {
+				continue
+			}
+			values = append(values, word)
+		}
+		return values
+	}
+	return nil
+}
+
 func (c *Command) setArgument() {
 	linesMap := c.scanHelpMessage("Usage:")
-	uppercasedPattern := regexp.MustCompile(`[A-Z_]+`)
-	lowercasedPattern := regexp.MustCompile(`[a-z_]+`)
-	var number int
 	var lines []string
 	for _, v := range linesMap {
 		lines = v
 		break
 	}
+	c.Arguments = parseArgumentNumber(c.ChainString(), lines)
+}
+
+// parseArgumentNumber reads a command's "Usage:" line(s) and ORs together
+// the ArgumentNumber* bits for every placeholder token it recognizes (e.g.
+// "CONTAINER" -> ArgumentNumberDockerContainer). Only HelpTextSource uses
+// it (through setArgument); JSONHelpSource reads argument types from its
+// metadata via jsonArgTypeBits instead.
+func parseArgumentNumber(chainString string, lines []string) int {
+	uppercasedPattern := regexp.MustCompile(`[A-Z_]+`)
+	lowercasedPattern := regexp.MustCompile(`[a-z_]+`)
+	var number int
 	for _, line := range lines {
 		line = strings.TrimSpace(line)
-		trimmed := strings.TrimPrefix(line, c.ChainString())
+		trimmed := strings.TrimPrefix(line, chainString)
 		if trimmed == line {
-			return
+			return 0
 		}
 		for _, match := range uppercasedPattern.FindAllString(trimmed, -1) {
 			switch match {
@@ -182,7 +678,7 @@ func (c *Command) setArgument() {
 			}
 		}
 	}
-	c.Arguments = number
+	return number
 }
 
 func (c *Command) setOptions() error {
@@ -210,10 +706,19 @@ func (c *Command) setOptions() error {
 			}
 			c.Options[len(c.Options)-1].Desc += " " + desc
 		} else {
+			values, valuesCommand := parseValuesFromDesc(desc), ""
+			if override, ok := FlagValueOverrides[long]; ok {
+				values, valuesCommand = override.Values, override.ValuesCommand
+			}
+			if override, ok := ChainOptionValueOverrides[chainOptionKey{c.chainSuffix(), long}]; ok {
+				values, valuesCommand = nil, override
+			}
 			c.Options = append(c.Options, &Option{
-				Desc:  desc,
-				Long:  long,
-				Short: short,
+				Desc:          desc,
+				Long:          long,
+				Short:         short,
+				Values:        values,
+				ValuesCommand: valuesCommand,
 			})
 		}
 	}
@@ -0,0 +1,17 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCompletionUsesCachedListing(t *testing.T) {
+	c := &Command{
+		Chain:     []string{"docker", "ps"},
+		Arguments: ArgumentNumberDockerContainer,
+	}
+	got := c.Completion()
+	if !strings.Contains(got, "__fish_docker_cached") {
+		t.Errorf("Completion() = %q, want it to route the container listing through __fish_docker_cached", got)
+	}
+}
@@ -0,0 +1,27 @@
+package cmd
+
+// FlagValueOverride describes the completion candidates for a flag's value:
+// either a static enumeration (Values) or a dynamic lookup expression
+// (ValuesCommand) to run in Fish, e.g. `(docker network ls --format=...)`.
+// The two are mutually exclusive.
+type FlagValueOverride struct {
+	Values        []string
+	ValuesCommand string
+}
+
+// FlagValueOverrides hand-maintains value completions for well-known Docker
+// flags whose help text doesn't spell out an enumeration (or whose
+// enumeration isn't phrased in a way setOptions' regex pass can parse). It's
+// kept separate from the parser so new flags can be added without touching
+// setOptions. Flags already covered by an inline "(a|b|c)" or "one of a, b,
+// c" in their description are derived automatically instead and don't need
+// an entry here; an entry here still takes precedence if both apply.
+var FlagValueOverrides = map[string]FlagValueOverride{
+	"restart":       {Values: []string{"no", "on-failure", "always", "unless-stopped"}},
+	"log-driver":    {Values: []string{"json-file", "syslog", "journald", "gelf", "fluentd", "awslogs", "splunk", "etwlogs", "none"}},
+	"network":       {ValuesCommand: "(docker network ls --format='{{.Name}}')"},
+	"volume-driver": {ValuesCommand: "(docker plugin ls --format='{{.Name}}')"},
+	"isolation":     {Values: []string{"default", "process", "hyperv"}},
+	"pull":          {Values: []string{"always", "missing", "never"}},
+	"platform":      {Values: []string{"linux/amd64", "linux/arm64", "linux/arm/v7", "windows/amd64"}},
+}
@@ -14,6 +14,20 @@ func main() {
 	}
 	fmt.Println(cmd.FishDockerCommandChainSatisfies)
 	fmt.Println(cmd.FishDockerCommandChainExactlyMatches)
+	fmt.Println(cmd.FishDockerContainerRmCandidates)
+	fmt.Println(cmd.FishDockerImageRmiCandidates)
+	fmt.Println(cmd.FishDockerNetworkConnectCandidates)
+	fmt.Println(cmd.FishDockerNetworkDisconnectCandidates)
+	fmt.Println(cmd.FishDockerComposeFile)
+	fmt.Println(cmd.FishDockerComposeFileCandidates)
+	fmt.Println(cmd.FishDockerComposeServices)
+	fmt.Println(cmd.FishDockerStackBundleFile)
+	fmt.Println(cmd.FishDockerStackBundleFileCandidates)
+	fmt.Println(cmd.FishDockerStackBundleServices)
+	fmt.Println(cmd.FishDockerStackServices)
+	fmt.Println(cmd.FishDockerDockerfileFile)
+	fmt.Println(cmd.FishDockerDockerfileStages)
+	fmt.Printf("%s\n", cmd.FishDockerCached)
 	fmt.Println("complete -c docker -f")
 	fmt.Println("complete -c docker -l help -d 'Print usage'")
 	traverse(c)